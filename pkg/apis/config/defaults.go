@@ -0,0 +1,319 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"knative.dev/pkg/ptr"
+)
+
+const (
+	// DefaultsConfigName is the name of the config map containing all
+	// defaults for resources.
+	DefaultsConfigName = "config-defaults"
+
+	// DefaultUserContainerName is the default name used for the user
+	// container if none is specified.
+	DefaultUserContainerName = "user-container"
+
+	// DefaultContainerConcurrency is the default concurrency used when no
+	// containerConcurrency is specified on a Revision.
+	DefaultContainerConcurrency = 0
+
+	// DefaultContainerConcurrencyMaxLimit is the default upper bound on
+	// containerConcurrency when none is specified; 0 means unbounded.
+	DefaultContainerConcurrencyMaxLimit = 0
+
+	// DefaultRevisionTimeoutSeconds is the default timeout used when no
+	// timeoutSeconds is specified on a RevisionSpec.
+	DefaultRevisionTimeoutSeconds = 300
+
+	// DefaultRevisionResponseStartTimeoutSeconds is the default time to
+	// first byte, when none is specified.
+	DefaultRevisionResponseStartTimeoutSeconds = 0
+
+	// DefaultRevisionIdleTimeoutSeconds is the default idle timeout, when
+	// none is specified.
+	DefaultRevisionIdleTimeoutSeconds = 0
+
+	// DefaultMaxRevisionTimeoutSeconds is the default maximum timeoutSeconds
+	// that may be specified on a RevisionSpec.
+	DefaultMaxRevisionTimeoutSeconds = 600
+
+	namespacesKeyInfix = ".namespaces."
+	selectorsKey       = "selectors"
+)
+
+// DefaultsFields holds the set of defaultable knobs that may be scoped
+// cluster-wide, per-namespace or per-label-selector. A nil field means "not
+// set at this scope", so that a narrower scope can override a subset of the
+// fields without having to repeat every value — including overriding a
+// field back to its zero value (e.g. container-concurrency: "0"), which a
+// bare int64 could not distinguish from "unset".
+type DefaultsFields struct {
+	RevisionTimeoutSeconds              *int64
+	RevisionResponseStartTimeoutSeconds *int64
+	RevisionIdleTimeoutSeconds          *int64
+	MaxRevisionTimeoutSeconds           *int64
+	ContainerConcurrency                *int64
+	ContainerConcurrencyMaxLimit        *int64
+	ContainerNameTemplate               *string
+}
+
+// SelectorOverride scopes a DefaultsFields override to objects whose labels
+// match Selector. When more than one SelectorOverride matches an object,
+// the first one in the list (as declared in the config-defaults ConfigMap)
+// wins.
+type SelectorOverride struct {
+	Selector labels.Selector
+	DefaultsFields
+}
+
+// Defaults includes the default values to be populated by the webhook.
+type Defaults struct {
+	DefaultsFields
+
+	// NamespaceOverrides holds per-namespace overrides of the cluster-wide
+	// fields above, keyed by namespace name. A namespace override always
+	// takes precedence over a matching SelectorOverride.
+	NamespaceOverrides map[string]DefaultsFields
+
+	// SelectorOverrides holds label-selector-scoped overrides of the
+	// cluster-wide fields, evaluated in declaration order.
+	SelectorOverrides []SelectorOverride
+}
+
+// Resolve returns the DefaultsFields that apply to an object in namespace
+// with the given labels: the cluster-wide defaults, overlaid first by the
+// first matching SelectorOverride (if any), then by the NamespaceOverrides
+// entry for namespace (if any). Unset (nil) fields at a narrower scope do
+// not clobber a wider scope's value; a field explicitly set to its zero
+// value at a narrower scope does.
+func (d *Defaults) Resolve(namespace string, objLabels map[string]string) DefaultsFields {
+	resolved := d.DefaultsFields
+
+	set := labels.Set(objLabels)
+	for _, so := range d.SelectorOverrides {
+		if so.Selector != nil && so.Selector.Matches(set) {
+			resolved = mergeDefaultsFields(resolved, so.DefaultsFields)
+			break
+		}
+	}
+
+	if override, ok := d.NamespaceOverrides[namespace]; ok {
+		resolved = mergeDefaultsFields(resolved, override)
+	}
+
+	return resolved
+}
+
+func mergeDefaultsFields(base, override DefaultsFields) DefaultsFields {
+	if override.RevisionTimeoutSeconds != nil {
+		base.RevisionTimeoutSeconds = override.RevisionTimeoutSeconds
+	}
+	if override.RevisionResponseStartTimeoutSeconds != nil {
+		base.RevisionResponseStartTimeoutSeconds = override.RevisionResponseStartTimeoutSeconds
+	}
+	if override.RevisionIdleTimeoutSeconds != nil {
+		base.RevisionIdleTimeoutSeconds = override.RevisionIdleTimeoutSeconds
+	}
+	if override.MaxRevisionTimeoutSeconds != nil {
+		base.MaxRevisionTimeoutSeconds = override.MaxRevisionTimeoutSeconds
+	}
+	if override.ContainerConcurrency != nil {
+		base.ContainerConcurrency = override.ContainerConcurrency
+	}
+	if override.ContainerConcurrencyMaxLimit != nil {
+		base.ContainerConcurrencyMaxLimit = override.ContainerConcurrencyMaxLimit
+	}
+	if override.ContainerNameTemplate != nil {
+		base.ContainerNameTemplate = override.ContainerNameTemplate
+	}
+	return base
+}
+
+// NewDefaultsConfigFromMap creates a Defaults from the supplied map,
+// expecting the keys and values used in the config-defaults ConfigMap. In
+// addition to the flat, cluster-wide keys (e.g. "revision-timeout-seconds"),
+// it accepts namespace-scoped variants of the form
+// "<key>.namespaces.<namespace>" (e.g.
+// "revision-timeout-seconds.namespaces.team-a") and a structured
+// "selectors" key holding a YAML/JSON list of label-selector-scoped
+// overrides.
+func NewDefaultsConfigFromMap(data map[string]string) (*Defaults, error) {
+	nc := &Defaults{NamespaceOverrides: map[string]DefaultsFields{}}
+
+	clusterWide := map[string]string{}
+	namespaced := map[string]map[string]string{}
+	for k, v := range data {
+		idx := strings.Index(k, namespacesKeyInfix)
+		if idx < 0 {
+			clusterWide[k] = v
+			continue
+		}
+		baseKey, ns := k[:idx], k[idx+len(namespacesKeyInfix):]
+		if namespaced[ns] == nil {
+			namespaced[ns] = map[string]string{}
+		}
+		namespaced[ns][baseKey] = v
+	}
+
+	if err := parseDefaultsFields(clusterWide, &nc.DefaultsFields); err != nil {
+		return nil, err
+	}
+	for ns, kv := range namespaced {
+		var fields DefaultsFields
+		if err := parseDefaultsFields(kv, &fields); err != nil {
+			return nil, fmt.Errorf("namespaces.%s: %w", ns, err)
+		}
+		nc.NamespaceOverrides[ns] = fields
+	}
+
+	if raw, ok := data[selectorsKey]; ok {
+		overrides, err := parseSelectorOverrides(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", selectorsKey, err)
+		}
+		nc.SelectorOverrides = overrides
+	}
+
+	// Fill in the fixed, hard-coded defaults for anything the ConfigMap
+	// left unset at the cluster-wide scope.
+	if nc.RevisionTimeoutSeconds == nil {
+		nc.RevisionTimeoutSeconds = ptr.Int64(DefaultRevisionTimeoutSeconds)
+	}
+	if nc.RevisionResponseStartTimeoutSeconds == nil {
+		nc.RevisionResponseStartTimeoutSeconds = ptr.Int64(DefaultRevisionResponseStartTimeoutSeconds)
+	}
+	if nc.RevisionIdleTimeoutSeconds == nil {
+		nc.RevisionIdleTimeoutSeconds = ptr.Int64(DefaultRevisionIdleTimeoutSeconds)
+	}
+	if nc.MaxRevisionTimeoutSeconds == nil {
+		nc.MaxRevisionTimeoutSeconds = ptr.Int64(DefaultMaxRevisionTimeoutSeconds)
+	}
+	if nc.ContainerConcurrency == nil {
+		nc.ContainerConcurrency = ptr.Int64(DefaultContainerConcurrency)
+	}
+	if nc.ContainerConcurrencyMaxLimit == nil {
+		nc.ContainerConcurrencyMaxLimit = ptr.Int64(DefaultContainerConcurrencyMaxLimit)
+	}
+	if nc.ContainerNameTemplate == nil {
+		nc.ContainerNameTemplate = ptr.String(DefaultUserContainerName)
+	}
+
+	return nc, nil
+}
+
+// parseDefaultsFields parses data (either the cluster-wide keys or one
+// namespace's "<key>.namespaces.<namespace>" keys, with the infix already
+// stripped) into fields, leaving a field nil when its key is absent from
+// data so that Resolve can tell "not set at this scope" apart from
+// "explicitly set to zero".
+func parseDefaultsFields(data map[string]string, fields *DefaultsFields) error {
+	var err error
+	setInt64 := func(key string, dst **int64) {
+		v, ok := data[key]
+		if !ok || err != nil {
+			return
+		}
+		n, perr := strconv.ParseInt(v, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("%s: %w", key, perr)
+			return
+		}
+		*dst = &n
+	}
+	setInt64("revision-timeout-seconds", &fields.RevisionTimeoutSeconds)
+	setInt64("revision-response-start-timeout-seconds", &fields.RevisionResponseStartTimeoutSeconds)
+	setInt64("revision-idle-timeout-seconds", &fields.RevisionIdleTimeoutSeconds)
+	setInt64("max-revision-timeout-seconds", &fields.MaxRevisionTimeoutSeconds)
+	setInt64("container-concurrency", &fields.ContainerConcurrency)
+	setInt64("container-concurrency-max-limit", &fields.ContainerConcurrencyMaxLimit)
+	if v, ok := data["container-name-template"]; ok {
+		fields.ContainerNameTemplate = &v
+	}
+	return err
+}
+
+// selectorOverrideYAML mirrors the shape of one entry of the "selectors"
+// list in the config-defaults ConfigMap.
+type selectorOverrideYAML struct {
+	MatchLabels map[string]string `json:"matchLabels"`
+
+	RevisionTimeoutSeconds              string `json:"revision-timeout-seconds,omitempty"`
+	RevisionResponseStartTimeoutSeconds string `json:"revision-response-start-timeout-seconds,omitempty"`
+	RevisionIdleTimeoutSeconds          string `json:"revision-idle-timeout-seconds,omitempty"`
+	MaxRevisionTimeoutSeconds           string `json:"max-revision-timeout-seconds,omitempty"`
+	ContainerConcurrency                string `json:"container-concurrency,omitempty"`
+	ContainerConcurrencyMaxLimit        string `json:"container-concurrency-max-limit,omitempty"`
+	ContainerNameTemplate               string `json:"container-name-template,omitempty"`
+}
+
+func parseSelectorOverrides(raw string) ([]SelectorOverride, error) {
+	var entries []selectorOverrideYAML
+	if err := parseYAMLOrJSON(raw, &entries); err != nil {
+		return nil, err
+	}
+
+	overrides := make([]SelectorOverride, 0, len(entries))
+	for i, e := range entries {
+		sel := labels.SelectorFromSet(labels.Set(e.MatchLabels))
+		fields, err := selectorOverrideFields(e)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		overrides = append(overrides, SelectorOverride{Selector: sel, DefaultsFields: fields})
+	}
+	return overrides, nil
+}
+
+func selectorOverrideFields(e selectorOverrideYAML) (DefaultsFields, error) {
+	var fields DefaultsFields
+	var err error
+	parseInt := func(s string, dst **int64) {
+		if s == "" || err != nil {
+			return
+		}
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 64)
+		if err == nil {
+			*dst = &n
+		}
+	}
+	parseInt(e.RevisionTimeoutSeconds, &fields.RevisionTimeoutSeconds)
+	parseInt(e.RevisionResponseStartTimeoutSeconds, &fields.RevisionResponseStartTimeoutSeconds)
+	parseInt(e.RevisionIdleTimeoutSeconds, &fields.RevisionIdleTimeoutSeconds)
+	parseInt(e.MaxRevisionTimeoutSeconds, &fields.MaxRevisionTimeoutSeconds)
+	parseInt(e.ContainerConcurrency, &fields.ContainerConcurrency)
+	parseInt(e.ContainerConcurrencyMaxLimit, &fields.ContainerConcurrencyMaxLimit)
+	if e.ContainerNameTemplate != "" {
+		fields.ContainerNameTemplate = &e.ContainerNameTemplate
+	}
+	return fields, err
+}
+
+// NewDefaultsConfigFromConfigMap creates a Defaults from the supplied
+// ConfigMap.
+func NewDefaultsConfigFromConfigMap(config *corev1.ConfigMap) (*Defaults, error) {
+	return NewDefaultsConfigFromMap(config.Data)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"knative.dev/pkg/configmap"
+)
+
+// FeaturesConfigName is the name of the config map containing all
+// feature flags.
+const FeaturesConfigName = "config-features"
+
+// Flag is an enabled/disabled feature-flag value.
+type Flag string
+
+const (
+	Enabled  Flag = "Enabled"
+	Disabled Flag = "Disabled"
+)
+
+// FeaturesFields holds the set of feature flags that may be scoped
+// cluster-wide, per-namespace or per-label-selector.
+type FeaturesFields struct {
+	MultiContainer        Flag
+	PodSpecAffinity       Flag
+	ResponsiveRevisionGC  Flag
+	TagHeaderBasedRouting Flag
+}
+
+// Features specifies the feature gates Knative Serving exposes to users.
+type Features struct {
+	FeaturesFields
+
+	// NamespaceOverrides holds per-namespace overrides of the cluster-wide
+	// flags above, keyed by namespace name.
+	NamespaceOverrides map[string]FeaturesFields
+
+	// SelectorOverrides holds label-selector-scoped overrides of the
+	// cluster-wide flags, evaluated in declaration order.
+	SelectorOverrides []FeaturesSelectorOverride
+}
+
+// FeaturesSelectorOverride scopes a FeaturesFields override to objects
+// whose labels match Selector.
+type FeaturesSelectorOverride struct {
+	Selector labels.Selector
+	FeaturesFields
+}
+
+// Resolve returns the FeaturesFields that apply to an object in namespace
+// with the given labels, following the same precedence as
+// Defaults.Resolve: cluster-wide, then the first matching selector
+// override, then the namespace override.
+func (f *Features) Resolve(namespace string, objLabels map[string]string) FeaturesFields {
+	resolved := f.FeaturesFields
+
+	set := labels.Set(objLabels)
+	for _, so := range f.SelectorOverrides {
+		if so.Selector != nil && so.Selector.Matches(set) {
+			resolved = mergeFeaturesFields(resolved, so.FeaturesFields)
+			break
+		}
+	}
+
+	if override, ok := f.NamespaceOverrides[namespace]; ok {
+		resolved = mergeFeaturesFields(resolved, override)
+	}
+
+	return resolved
+}
+
+func mergeFeaturesFields(base, override FeaturesFields) FeaturesFields {
+	if override.MultiContainer != "" {
+		base.MultiContainer = override.MultiContainer
+	}
+	if override.PodSpecAffinity != "" {
+		base.PodSpecAffinity = override.PodSpecAffinity
+	}
+	if override.ResponsiveRevisionGC != "" {
+		base.ResponsiveRevisionGC = override.ResponsiveRevisionGC
+	}
+	if override.TagHeaderBasedRouting != "" {
+		base.TagHeaderBasedRouting = override.TagHeaderBasedRouting
+	}
+	return base
+}
+
+// NewFeaturesConfigFromMap creates a Features from the supplied map,
+// accepting the same "<key>.namespaces.<namespace>" and "selectors"
+// conventions as NewDefaultsConfigFromMap.
+func NewFeaturesConfigFromMap(data map[string]string) (*Features, error) {
+	nf := &Features{NamespaceOverrides: map[string]FeaturesFields{}}
+
+	clusterWide := map[string]string{}
+	namespaced := map[string]map[string]string{}
+	for k, v := range data {
+		idx := strings.Index(k, namespacesKeyInfix)
+		if idx < 0 {
+			clusterWide[k] = v
+			continue
+		}
+		baseKey, ns := k[:idx], k[idx+len(namespacesKeyInfix):]
+		if namespaced[ns] == nil {
+			namespaced[ns] = map[string]string{}
+		}
+		namespaced[ns][baseKey] = v
+	}
+
+	if err := parseFeaturesFields(clusterWide, &nf.FeaturesFields); err != nil {
+		return nil, err
+	}
+	for ns, kv := range namespaced {
+		var fields FeaturesFields
+		if err := parseFeaturesFields(kv, &fields); err != nil {
+			return nil, fmt.Errorf("namespaces.%s: %w", ns, err)
+		}
+		nf.NamespaceOverrides[ns] = fields
+	}
+
+	if raw, ok := data[selectorsKey]; ok {
+		overrides, err := parseFeaturesSelectorOverrides(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", selectorsKey, err)
+		}
+		nf.SelectorOverrides = overrides
+	}
+
+	return nf, nil
+}
+
+func parseFeaturesFields(data map[string]string, fields *FeaturesFields) error {
+	asFlag := func(key string, dst *Flag) configmap.ParseFunc {
+		return func(data map[string]string) error {
+			if raw, ok := data[key]; ok {
+				*dst = Flag(raw)
+			}
+			return nil
+		}
+	}
+	return configmap.Parse(data,
+		asFlag("multi-container", &fields.MultiContainer),
+		asFlag("pod-spec-affinity", &fields.PodSpecAffinity),
+		asFlag("responsive-revision-gc", &fields.ResponsiveRevisionGC),
+		asFlag("tag-header-based-routing", &fields.TagHeaderBasedRouting),
+	)
+}
+
+type featuresSelectorOverrideYAML struct {
+	MatchLabels map[string]string `json:"matchLabels"`
+
+	MultiContainer        string `json:"multi-container,omitempty"`
+	PodSpecAffinity       string `json:"pod-spec-affinity,omitempty"`
+	ResponsiveRevisionGC  string `json:"responsive-revision-gc,omitempty"`
+	TagHeaderBasedRouting string `json:"tag-header-based-routing,omitempty"`
+}
+
+func parseFeaturesSelectorOverrides(raw string) ([]FeaturesSelectorOverride, error) {
+	var entries []featuresSelectorOverrideYAML
+	if err := parseYAMLOrJSON(raw, &entries); err != nil {
+		return nil, err
+	}
+
+	overrides := make([]FeaturesSelectorOverride, 0, len(entries))
+	for _, e := range entries {
+		sel := labels.SelectorFromSet(labels.Set(e.MatchLabels))
+		overrides = append(overrides, FeaturesSelectorOverride{
+			Selector: sel,
+			FeaturesFields: FeaturesFields{
+				MultiContainer:        Flag(e.MultiContainer),
+				PodSpecAffinity:       Flag(e.PodSpecAffinity),
+				ResponsiveRevisionGC:  Flag(e.ResponsiveRevisionGC),
+				TagHeaderBasedRouting: Flag(e.TagHeaderBasedRouting),
+			},
+		})
+	}
+	return overrides, nil
+}
+
+// NewFeaturesConfigFromConfigMap creates a Features from the supplied
+// ConfigMap.
+func NewFeaturesConfigFromConfigMap(config *corev1.ConfigMap) (*Features, error) {
+	return NewFeaturesConfigFromMap(config.Data)
+}
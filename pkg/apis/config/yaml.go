@@ -0,0 +1,25 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import "sigs.k8s.io/yaml"
+
+// parseYAMLOrJSON unmarshals raw, which may be either YAML or JSON (YAML
+// being a superset of JSON), into out.
+func parseYAMLOrJSON(raw string, out interface{}) error {
+	return yaml.Unmarshal([]byte(raw), out)
+}
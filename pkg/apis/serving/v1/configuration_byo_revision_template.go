@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"knative.dev/pkg/apis"
+)
+
+// RevisionTemplateAnnotationKey records the pre-expansion
+// spec.template.metadata.name, exactly as supplied by the user.
+// SetDefaultRevisionName writes it whenever it expands a template; nothing
+// in this package reads it back, so a later update whose
+// spec.template.metadata.name has no expansion tokens is not re-expanded
+// from it.
+const RevisionTemplateAnnotationKey = "serving.knative.dev/revisionNameTemplate"
+
+// revisionNameTemplateData is the data made available to the
+// spec.template.metadata.name expansion tokens: {{.Configuration}},
+// {{.Generation}}, {{.ImageDigest}} and {{.Timestamp}}.
+type revisionNameTemplateData struct {
+	// Configuration is the name of the owning Configuration.
+	Configuration string
+	// Generation is the generation the Revision being created will carry.
+	Generation string
+	// ImageDigest is the first 12 characters of the first container's
+	// image digest, or "" if the image isn't pinned by digest.
+	ImageDigest string
+	// Timestamp is the current time, formatted as "20060102150405".
+	Timestamp string
+}
+
+var revisionNameTokenRE = regexp.MustCompile(`{{\s*\.\w+\s*}}`)
+
+// hasRevisionNameTemplate reports whether name contains at least one of the
+// supported expansion tokens.
+func hasRevisionNameTemplate(name string) bool {
+	return revisionNameTokenRE.MatchString(name)
+}
+
+// SetDefaultRevisionName expands the expansion tokens in
+// spec.template.metadata.name, if any are present, recording the
+// pre-expansion template in RevisionTemplateAnnotationKey. The template
+// text itself is expected to stay the same across applies (e.g.
+// `app-{{.ImageDigest}}` committed once to a manifest) while the values it
+// expands to change from under it — a new image digest, an advancing
+// Generation — so the expansion is recomputed on every call rather than
+// reused whenever the raw template text is unchanged; reusing it by
+// template text alone would freeze the expanded name at whatever it was on
+// the first apply and defeat the "one revision per digest/generation" use
+// case this supports. It is a no-op when spec.template.metadata.name has
+// no expansion tokens, which includes the plain BYO revision name case
+// handled by SetDefaults already.
+func (c *Configuration) SetDefaultRevisionName(ctx context.Context) error {
+	name := c.Spec.Template.ObjectMeta.Name
+	if !hasRevisionNameTemplate(name) {
+		return nil
+	}
+
+	expanded, err := expandRevisionName(name, c)
+	if err != nil {
+		return err
+	}
+
+	c.setRevisionNameTemplateAnnotation(name)
+	c.Spec.Template.ObjectMeta.Name = expanded
+	return nil
+}
+
+// expandRevisionName expands tmpl, the pre-expansion
+// spec.template.metadata.name, using c's own name, its next generation, its
+// first container's image digest and the current time.
+func expandRevisionName(tmpl string, c *Configuration) (string, error) {
+	t, err := template.New("revisionName").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parsing spec.template.metadata.name: %w", err)
+	}
+
+	data := revisionNameTemplateData{
+		Configuration: c.Name,
+		Generation:    strconv.FormatInt(c.Generation+1, 10),
+		ImageDigest:   firstContainerImageDigest(c),
+		Timestamp:     time.Now().UTC().Format("20060102150405"),
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("expanding spec.template.metadata.name: %w", err)
+	}
+	return strings.ToLower(buf.String()), nil
+}
+
+// firstContainerImageDigest returns the first 12 characters of the first
+// container's image digest, or "" if there is no first container or its
+// image isn't pinned by digest (e.g. it is still a tag at defaulting time).
+func firstContainerImageDigest(c *Configuration) string {
+	containers := c.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return ""
+	}
+	const sep = "@sha256:"
+	image := containers[0].Image
+	idx := strings.LastIndex(image, sep)
+	if idx < 0 {
+		return ""
+	}
+	digest := image[idx+len(sep):]
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return digest
+}
+
+func (c *Configuration) setRevisionNameTemplateAnnotation(tmpl string) {
+	if c.Annotations == nil {
+		c.Annotations = map[string]string{}
+	}
+	c.Annotations[RevisionTemplateAnnotationKey] = tmpl
+}
+
+// ValidateRevisionName validates that, once expanded,
+// spec.template.metadata.name is a valid DNS-1123 label. It is meant to be
+// called from Configuration.Validate after SetDefaults has run.
+func (c *Configuration) ValidateRevisionName(ctx context.Context) *apis.FieldError {
+	name := c.Spec.Template.ObjectMeta.Name
+	if name == "" {
+		return nil
+	}
+	if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("invalid value: %s", strings.Join(msgs, ", ")),
+			Paths:   []string{"spec.template.metadata.name"},
+		}
+	}
+	return nil
+}
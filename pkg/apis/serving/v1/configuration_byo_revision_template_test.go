@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+)
+
+func templatedConfiguration(tmpl string) *Configuration {
+	return &Configuration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "thing",
+			Generation: 1,
+		},
+		Spec: ConfigurationSpec{
+			Template: RevisionTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: tmpl,
+				},
+				Spec: RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Image: "busybox@sha256:" + "abcdef0123456789",
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSetDefaultRevisionNameExpandsTokens(t *testing.T) {
+	c := templatedConfiguration("thing-{{.Generation}}-{{.ImageDigest}}")
+
+	if err := c.SetDefaultRevisionName(context.Background()); err != nil {
+		t.Fatalf("SetDefaultRevisionName() = %v", err)
+	}
+
+	if want, got := "thing-2-abcdef012345", c.Spec.Template.ObjectMeta.Name; got != want {
+		t.Errorf("Spec.Template.ObjectMeta.Name = %q, want %q", got, want)
+	}
+	if got, want := c.Annotations[RevisionTemplateAnnotationKey], "thing-{{.Generation}}-{{.ImageDigest}}"; got != want {
+		t.Errorf("annotation = %q, want %q", got, want)
+	}
+}
+
+func TestSetDefaultRevisionNameStableWhenInputsUnchanged(t *testing.T) {
+	const tmpl = "thing-{{.Generation}}-{{.ImageDigest}}"
+
+	old := templatedConfiguration(tmpl)
+	if err := old.SetDefaultRevisionName(context.Background()); err != nil {
+		t.Fatalf("SetDefaultRevisionName() on old = %v", err)
+	}
+
+	next := templatedConfiguration(tmpl)
+	ctx := apis.WithinUpdate(context.Background(), old)
+	if err := next.SetDefaultRevisionName(ctx); err != nil {
+		t.Fatalf("SetDefaultRevisionName() on next = %v", err)
+	}
+
+	if got, want := next.Spec.Template.ObjectMeta.Name, old.Spec.Template.ObjectMeta.Name; got != want {
+		t.Errorf("re-defaulting an unchanged manifest recomputed a different name: got %q, want %q", got, want)
+	}
+}
+
+func TestSetDefaultRevisionNameRecomputesOnDigestChange(t *testing.T) {
+	const tmpl = "thing-{{.ImageDigest}}"
+
+	old := templatedConfiguration(tmpl)
+	if err := old.SetDefaultRevisionName(context.Background()); err != nil {
+		t.Fatalf("SetDefaultRevisionName() on old = %v", err)
+	}
+
+	next := templatedConfiguration(tmpl)
+	next.Spec.Template.Spec.Containers[0].Image = "busybox@sha256:" + "fedcba9876543210"
+	ctx := apis.WithinUpdate(context.Background(), old)
+	if err := next.SetDefaultRevisionName(ctx); err != nil {
+		t.Fatalf("SetDefaultRevisionName() on next = %v", err)
+	}
+
+	if next.Spec.Template.ObjectMeta.Name == old.Spec.Template.ObjectMeta.Name {
+		t.Errorf("new image digest should produce a new revision name, got the stale reused name %q", old.Spec.Template.ObjectMeta.Name)
+	}
+}
+
+func TestSetDefaultRevisionNameRecomputesOnGenerationChange(t *testing.T) {
+	const tmpl = "thing-{{.Generation}}"
+
+	old := templatedConfiguration(tmpl)
+	if err := old.SetDefaultRevisionName(context.Background()); err != nil {
+		t.Fatalf("SetDefaultRevisionName() on old = %v", err)
+	}
+
+	next := templatedConfiguration(tmpl)
+	next.Generation = old.Generation + 1
+	ctx := apis.WithinUpdate(context.Background(), old)
+	if err := next.SetDefaultRevisionName(ctx); err != nil {
+		t.Fatalf("SetDefaultRevisionName() on next = %v", err)
+	}
+
+	if next.Spec.Template.ObjectMeta.Name == old.Spec.Template.ObjectMeta.Name {
+		t.Errorf("an advanced Generation should produce a new revision name, got the stale reused name %q", old.Spec.Template.ObjectMeta.Name)
+	}
+}
+
+func TestSetDefaultRevisionNameNoTemplateIsNoOp(t *testing.T) {
+	c := templatedConfiguration("thing-2022")
+	if err := c.SetDefaultRevisionName(context.Background()); err != nil {
+		t.Fatalf("SetDefaultRevisionName() = %v", err)
+	}
+	if got, want := c.Spec.Template.ObjectMeta.Name, "thing-2022"; got != want {
+		t.Errorf("Spec.Template.ObjectMeta.Name = %q, want %q", got, want)
+	}
+	if _, ok := c.Annotations[RevisionTemplateAnnotationKey]; ok {
+		t.Error("expected no RevisionTemplateAnnotationKey annotation for a non-templated name")
+	}
+}
+
+func TestValidateRevisionNameRejectsNonDNS1123(t *testing.T) {
+	c := templatedConfiguration("Thing_Not_DNS1123")
+	if err := c.ValidateRevisionName(context.Background()); err == nil {
+		t.Error("ValidateRevisionName() = nil, want an error for an invalid DNS-1123 label")
+	}
+}
+
+func TestValidateRevisionNameAcceptsExpandedName(t *testing.T) {
+	c := templatedConfiguration("thing-{{.Generation}}")
+	if err := c.SetDefaultRevisionName(context.Background()); err != nil {
+		t.Fatalf("SetDefaultRevisionName() = %v", err)
+	}
+	if err := c.ValidateRevisionName(context.Background()); err != nil {
+		t.Errorf("ValidateRevisionName() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+// SetDefaults implements apis.Defaultable. It resolves any namespace- or
+// label-selector-scoped config-defaults/config-features override for c's
+// own namespace and labels before deferring the usual
+// container/resources/probe/timeout defaulting to spec.template.spec, then
+// expands spec.template.metadata.name's BYO revision name template tokens,
+// if any, and finally records the creator/updater user info annotations.
+func (c *Configuration) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, c.ObjectMeta)
+	ctx = c.withResolvedDefaults(ctx)
+
+	var prevSpec *ConfigurationSpec
+	if prev, ok := apis.GetBaseline(ctx).(*Configuration); ok && prev != nil {
+		prevSpec = &prev.Spec
+	}
+
+	c.Spec.SetDefaults(ctx)
+
+	// A malformed template is left unexpanded here; ValidateRevisionName,
+	// called from Validate, is what rejects it, the same way every other
+	// field SetDefaults applies best-effort is only validated afterwards.
+	_ = c.SetDefaultRevisionName(ctx)
+
+	serving.SetUserInfo(ctx, prevSpec, &c.Spec, c)
+}
+
+// Validate implements apis.Validatable.
+func (c *Configuration) Validate(ctx context.Context) *apis.FieldError {
+	errs := c.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec")
+	return errs.Also(c.ValidateRevisionName(ctx))
+}
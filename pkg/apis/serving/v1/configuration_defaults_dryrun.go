@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	apicfg "knative.dev/serving/pkg/apis/config"
+	cconfig "knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+// ChangeSource classifies why SetDefaultsDryRun changed a field.
+type ChangeSource string
+
+const (
+	// SourceBuiltIn marks a change made by a fixed, hard-coded default
+	// (e.g. the user container name, or the default readiness probe).
+	SourceBuiltIn ChangeSource = "BuiltIn"
+
+	// SourceConfigDefaults marks a change sourced from a key in the
+	// config-defaults ConfigMap (see pkg/apis/config.Defaults), including
+	// any namespace- or label-selector-scoped override of it.
+	SourceConfigDefaults ChangeSource = "ConfigDefaults"
+
+	// SourceFeatureFlag marks a change that was only applied because a
+	// feature flag in config-features gated it on.
+	SourceFeatureFlag ChangeSource = "FeatureFlag"
+
+	// SourcePreviousRevision marks a change to the BYO revision name in
+	// spec.template.metadata.name, such as the expansion of its template
+	// tokens by SetDefaultRevisionName, on create as well as on update.
+	SourcePreviousRevision ChangeSource = "PreviousRevision"
+)
+
+// DefaultChange records a single field that SetDefaultsDryRun would change
+// if SetDefaults were called for real.
+type DefaultChange struct {
+	// Path is a JSONPath-like dotted path to the field that changed, e.g.
+	// "spec.template.spec.containers[0].name".
+	Path string `json:"path"`
+
+	// Old is the value of the field before defaulting. For an unset field it
+	// is that field's zero value (e.g. a nil pointer or an empty string); it
+	// is a nil interface only for a container that did not exist before.
+	Old interface{} `json:"old,omitempty"`
+
+	// New is the value of the field after defaulting.
+	New interface{} `json:"new"`
+
+	// Source identifies why the controller would apply this change.
+	Source ChangeSource `json:"source"`
+}
+
+// SetDefaultsDryRun returns the Configuration that SetDefaults would
+// produce, along with the list of changes it would make, without mutating
+// the receiver. It is the basis of the webhook's `?dryRun=defaults`
+// admission subresource, which lets users preview the implicit rewrites
+// (container name, resources, readiness probe, timeouts,
+// EnableServiceLinks on create, BYO revision name expansion, ...) that the
+// controller applies today with no other way to introspect them.
+func (c *Configuration) SetDefaultsDryRun(ctx context.Context) (*Configuration, []DefaultChange, error) {
+	before := c.DeepCopy()
+	after := c.DeepCopy()
+	after.SetDefaults(ctx)
+
+	changes := diffConfiguration(ctx, before, after)
+	return after, changes, nil
+}
+
+func diffConfiguration(ctx context.Context, before, after *Configuration) []DefaultChange {
+	var changes []DefaultChange
+
+	cfg := cconfig.FromContextOrDefaults(after.withResolvedDefaults(ctx))
+
+	beforeSpec, afterSpec := &before.Spec.Template.Spec, &after.Spec.Template.Spec
+
+	if !reflect.DeepEqual(beforeSpec.TimeoutSeconds, afterSpec.TimeoutSeconds) {
+		changes = append(changes, DefaultChange{
+			Path: "spec.template.spec.timeoutSeconds", Old: beforeSpec.TimeoutSeconds,
+			New: afterSpec.TimeoutSeconds, Source: SourceConfigDefaults,
+		})
+	}
+	if !reflect.DeepEqual(beforeSpec.ContainerConcurrency, afterSpec.ContainerConcurrency) {
+		changes = append(changes, DefaultChange{
+			Path: "spec.template.spec.containerConcurrency", Old: beforeSpec.ContainerConcurrency,
+			New: afterSpec.ContainerConcurrency, Source: SourceConfigDefaults,
+		})
+	}
+	if !reflect.DeepEqual(beforeSpec.EnableServiceLinks, afterSpec.EnableServiceLinks) {
+		changes = append(changes, DefaultChange{
+			Path: "spec.template.spec.enableServiceLinks", Old: beforeSpec.EnableServiceLinks,
+			New: afterSpec.EnableServiceLinks, Source: SourceBuiltIn,
+		})
+	}
+
+	for i := range afterSpec.Containers {
+		path := containerPath(i)
+		var beforeContainer interface{}
+		if i < len(beforeSpec.Containers) {
+			beforeContainer = beforeSpec.Containers[i].Name
+		}
+		afterContainer := afterSpec.Containers[i].Name
+		if !reflect.DeepEqual(beforeContainer, afterContainer) {
+			changes = append(changes, DefaultChange{
+				Path: path + ".name", Old: beforeContainer, New: afterContainer, Source: containerNameSource(cfg, i),
+			})
+		}
+
+		var beforeResources interface{}
+		if i < len(beforeSpec.Containers) {
+			beforeResources = beforeSpec.Containers[i].Resources
+		}
+		afterResources := afterSpec.Containers[i].Resources
+		if !reflect.DeepEqual(beforeResources, afterResources) {
+			changes = append(changes, DefaultChange{
+				Path: path + ".resources", Old: beforeResources, New: afterResources, Source: SourceConfigDefaults,
+			})
+		}
+
+		var beforeProbe interface{}
+		if i < len(beforeSpec.Containers) {
+			beforeProbe = beforeSpec.Containers[i].ReadinessProbe
+		}
+		afterProbe := afterSpec.Containers[i].ReadinessProbe
+		if !reflect.DeepEqual(beforeProbe, afterProbe) {
+			changes = append(changes, DefaultChange{
+				Path: path + ".readinessProbe", Old: beforeProbe, New: afterProbe, Source: SourceBuiltIn,
+			})
+		}
+	}
+
+	if before.Spec.Template.ObjectMeta.Name != after.Spec.Template.ObjectMeta.Name {
+		changes = append(changes, DefaultChange{
+			Path: "spec.template.metadata.name", Old: before.Spec.Template.ObjectMeta.Name,
+			New: after.Spec.Template.ObjectMeta.Name, Source: SourcePreviousRevision,
+		})
+	}
+
+	return changes
+}
+
+func containerPath(i int) string {
+	return "spec.template.spec.containers[" + strconv.Itoa(i) + "]"
+}
+
+// containerNameSource reports why the container at index i in
+// spec.template.spec.containers would get a defaulted name. The first
+// container's name comes from config-defaults' container-name-template key
+// (ContainerNameTemplate) unless that key is left at its built-in value, in
+// which case it's the hard-coded apicfg.DefaultUserContainerName. Any
+// container beyond the first is only named at all because config-features'
+// multi-container flag is enabled, so its defaulted name is attributed to
+// that flag rather than to config-defaults.
+func containerNameSource(cfg *cconfig.Config, i int) ChangeSource {
+	if i > 0 {
+		return SourceFeatureFlag
+	}
+	if tmpl := cfg.Defaults.ContainerNameTemplate; tmpl != nil && *tmpl != apicfg.DefaultUserContainerName {
+		return SourceConfigDefaults
+	}
+	return SourceBuiltIn
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"knative.dev/pkg/apis"
+	apicfg "knative.dev/serving/pkg/apis/config"
+	cconfig "knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+func TestSetDefaultsDryRunDoesNotMutate(t *testing.T) {
+	in := &Configuration{
+		Spec: ConfigurationSpec{
+			Template: RevisionTemplateSpec{
+				Spec: RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Image: "busybox",
+						}},
+					},
+				},
+			},
+		},
+	}
+	before := in.DeepCopy()
+
+	defaulted, changes, err := in.SetDefaultsDryRun(apis.WithinCreate(context.Background()))
+	if err != nil {
+		t.Fatalf("SetDefaultsDryRun() = %v", err)
+	}
+
+	if diff := cmp.Diff(before, in); diff != "" {
+		t.Errorf("SetDefaultsDryRun() mutated the receiver (-want +got): %s", diff)
+	}
+	if len(changes) == 0 {
+		t.Error("SetDefaultsDryRun() returned no changes, want at least the container name/resources/probe defaults")
+	}
+
+	want := in.DeepCopy()
+	want.SetDefaults(apis.WithinCreate(context.Background()))
+	if diff := cmp.Diff(want, defaulted, ignoreUnexportedResources); diff != "" {
+		t.Errorf("SetDefaultsDryRun() result (-want +got): %s", diff)
+	}
+}
+
+func TestDiffConfigurationContainerNameSource(t *testing.T) {
+	newConfiguration := func() *Configuration {
+		return &Configuration{
+			Spec: ConfigurationSpec{
+				Template: RevisionTemplateSpec{
+					Spec: RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							Containers: []corev1.Container{{Image: "busybox"}, {Image: "sidecar"}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("built-in name", func(t *testing.T) {
+		in := newConfiguration()
+		_, changes, err := in.SetDefaultsDryRun(apis.WithinCreate(context.Background()))
+		if err != nil {
+			t.Fatalf("SetDefaultsDryRun() = %v", err)
+		}
+		assertChangeSource(t, changes, "spec.template.spec.containers[0].name", SourceBuiltIn)
+		assertChangeSource(t, changes, "spec.template.spec.containers[1].name", SourceFeatureFlag)
+	})
+
+	t.Run("config-defaults container-name-template", func(t *testing.T) {
+		defaults, err := apicfg.NewDefaultsConfigFromMap(map[string]string{
+			"container-name-template": "main",
+		})
+		if err != nil {
+			t.Fatalf("NewDefaultsConfigFromMap() = %v", err)
+		}
+		features, _ := apicfg.NewFeaturesConfigFromMap(map[string]string{})
+		ctx := cconfig.ToContext(apis.WithinCreate(context.Background()), &cconfig.Config{Defaults: defaults, Features: features})
+
+		in := newConfiguration()
+		_, changes, err := in.SetDefaultsDryRun(ctx)
+		if err != nil {
+			t.Fatalf("SetDefaultsDryRun() = %v", err)
+		}
+		assertChangeSource(t, changes, "spec.template.spec.containers[0].name", SourceConfigDefaults)
+	})
+}
+
+func assertChangeSource(t *testing.T, changes []DefaultChange, path string, want ChangeSource) {
+	t.Helper()
+	for _, c := range changes {
+		if c.Path == path {
+			if c.Source != want {
+				t.Errorf("changes[%q].Source = %v, want %v", path, c.Source, want)
+			}
+			return
+		}
+	}
+	t.Errorf("no change found for path %q", path)
+}
+
+func TestSetDefaultsDryRunNoChanges(t *testing.T) {
+	in := &Configuration{}
+	in.SetDefaults(context.Background())
+
+	_, changes, err := in.SetDefaultsDryRun(context.Background())
+	if err != nil {
+		t.Fatalf("SetDefaultsDryRun() = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("SetDefaultsDryRun() on an already-defaulted object = %v, want no changes", changes)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	cconfig "knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+// withResolvedDefaults attaches a Config to ctx whose Defaults and Features
+// have already been narrowed to the fields that apply to c's own namespace
+// and labels (see config.Defaults.Resolve and config.Features.Resolve), so
+// that the rest of the defaulting chain — which reads config-defaults and
+// config-features values from the context rather than from the ConfigMaps
+// directly — transparently becomes namespace- and label-selector-aware
+// without having to thread c through every defaulting function. SetDefaults
+// calls this before defaulting spec.template.spec so that a
+// RevisionSpec/PodSpec default sourced from config-defaults or gated by a
+// config-features flag honors any namespace or label-selector override for
+// c.
+func (c *Configuration) withResolvedDefaults(ctx context.Context) context.Context {
+	cfg := cconfig.FromContextOrDefaults(ctx)
+	resolved := *cfg
+
+	defaults := *cfg.Defaults
+	defaults.DefaultsFields = cfg.Defaults.Resolve(c.Namespace, c.Labels)
+	resolved.Defaults = &defaults
+
+	features := *cfg.Features
+	features.FeaturesFields = cfg.Features.Resolve(c.Namespace, c.Labels)
+	resolved.Features = &features
+
+	return cconfig.ToContext(ctx, &resolved)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// Export serializes cfg together with revisions into the document shape
+// selected by mode. The Configuration is re-defaulted before export (via
+// Configuration.SetDefaults) so that the emitted spec is self-contained and
+// does not depend on the target cluster's config-defaults ConfigMap having
+// the same values as the source cluster.
+//
+// revisions need not be sorted; Export orders them by
+// serving.ConfigurationGenerationLabelKey, oldest first.
+func Export(ctx context.Context, cfg *servingv1.Configuration, revisions []*servingv1.Revision, mode Mode) (runtime.Object, error) {
+	cfg = cfg.DeepCopy()
+	cfg.SetDefaults(ctx)
+	sanitizeObjectMeta(&cfg.ObjectMeta)
+	cfg.Status = servingv1.ConfigurationStatus{}
+
+	sorted := make([]*servingv1.Revision, len(revisions))
+	copy(sorted, revisions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return generation(sorted[i]) < generation(sorted[j])
+	})
+
+	switch mode {
+	case Resources:
+		return exportResources(cfg, sorted)
+	case Kubernetes:
+		return exportKubernetes(cfg, sorted)
+	default:
+		return nil, fmt.Errorf("export: unknown mode %q", mode)
+	}
+}
+
+func exportResources(cfg *servingv1.Configuration, revisions []*servingv1.Revision) (*Resource, error) {
+	out := &Resource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: servingv1.SchemeGroupVersion.String(),
+			Kind:       "ConfigurationExport",
+		},
+		Configuration: *cfg,
+		Revisions:     make([]servingv1.Revision, 0, len(revisions)),
+	}
+	for _, rev := range revisions {
+		rev = rev.DeepCopy()
+		sanitizeObjectMeta(&rev.ObjectMeta)
+		rev.Status = servingv1.RevisionStatus{}
+		out.Revisions = append(out.Revisions, *rev)
+	}
+	return out, nil
+}
+
+func exportKubernetes(cfg *servingv1.Configuration, revisions []*servingv1.Revision) (*metav1.List, error) {
+	out := &metav1.List{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "List",
+		},
+	}
+	cfg.TypeMeta = metav1.TypeMeta{
+		APIVersion: servingv1.SchemeGroupVersion.String(),
+		Kind:       "Configuration",
+	}
+	cfgRaw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("export: encoding configuration %s: %w", cfg.Name, err)
+	}
+	out.Items = append(out.Items, runtime.RawExtension{Raw: cfgRaw})
+
+	for _, rev := range revisions {
+		rev = rev.DeepCopy()
+		sanitizeObjectMeta(&rev.ObjectMeta)
+		rev.Status = servingv1.RevisionStatus{}
+		rev.TypeMeta = metav1.TypeMeta{
+			APIVersion: servingv1.SchemeGroupVersion.String(),
+			Kind:       "Revision",
+		}
+		raw, err := json.Marshal(rev)
+		if err != nil {
+			return nil, fmt.Errorf("export: encoding revision %s: %w", rev.Name, err)
+		}
+		out.Items = append(out.Items, runtime.RawExtension{Raw: raw})
+	}
+	return out, nil
+}
+
+// generation returns the Revision's configurationGeneration as parsed from
+// its serving.knative.dev/configurationGeneration label, or 0 if absent or
+// malformed.
+func generation(rev *servingv1.Revision) int64 {
+	v, ok := rev.Labels[serving.ConfigurationGenerationLabelKey]
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// sanitizeObjectMeta strips the cluster-assigned fields that must not be
+// replayed against a different cluster, while preserving the name (so BYO
+// revision names survive the round trip), namespace, labels and
+// annotations.
+func sanitizeObjectMeta(om *metav1.ObjectMeta) {
+	om.SelfLink = ""
+	om.UID = ""
+	om.ResourceVersion = ""
+	om.Generation = 0
+	om.CreationTimestamp = metav1.Time{}
+	om.DeletionTimestamp = nil
+	om.DeletionGracePeriodSeconds = nil
+	om.OwnerReferences = nil
+	om.ManagedFields = nil
+}
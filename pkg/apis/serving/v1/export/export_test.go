@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func byoConfiguration() (*servingv1.Configuration, []*servingv1.Revision) {
+	cfg := &servingv1.Configuration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "thing",
+			Namespace: "default",
+		},
+		Spec: servingv1.ConfigurationSpec{
+			Template: servingv1.RevisionTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "thing-00002",
+				},
+				Spec: servingv1.RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Image: "busybox",
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	revs := []*servingv1.Revision{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "thing-00001",
+			Namespace: "default",
+			Labels:    map[string]string{serving.ConfigurationGenerationLabelKey: "1"},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "thing-00002",
+			Namespace: "default",
+			Labels:    map[string]string{serving.ConfigurationGenerationLabelKey: "2"},
+		},
+	}}
+	return cfg, revs
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, mode := range []Mode{Resources, Kubernetes} {
+		t.Run(string(mode), func(t *testing.T) {
+			cfg, revs := byoConfiguration()
+
+			doc, err := Export(context.Background(), cfg, revs, mode)
+			if err != nil {
+				t.Fatalf("Export() = %v", err)
+			}
+
+			gotCfg, gotRevs, err := Import(doc)
+			if err != nil {
+				t.Fatalf("Import() = %v", err)
+			}
+
+			if got, want := gotCfg.Spec.Template.ObjectMeta.Name, cfg.Spec.Template.ObjectMeta.Name; got != want {
+				t.Errorf("BYO revision name = %q, want %q", got, want)
+			}
+			if got, want := len(gotRevs), len(revs); got != want {
+				t.Fatalf("len(revisions) = %d, want %d", got, want)
+			}
+			for i, want := range revs {
+				if got := gotRevs[i].Name; got != want.Name {
+					t.Errorf("revisions[%d].Name = %q, want %q", i, got, want.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestExportAppliesDefaulting(t *testing.T) {
+	cfg, _ := byoConfiguration()
+
+	doc, err := Export(context.Background(), cfg, nil, Resources)
+	if err != nil {
+		t.Fatalf("Export() = %v", err)
+	}
+	resource, ok := doc.(*Resource)
+	if !ok {
+		t.Fatalf("Export() returned %T, want *Resource", doc)
+	}
+
+	want := cfg.DeepCopy()
+	want.SetDefaults(context.Background())
+	if diff := cmp.Diff(want.Spec, resource.Configuration.Spec); diff != "" {
+		t.Errorf("exported spec was not defaulted (-want +got): %s", diff)
+	}
+}
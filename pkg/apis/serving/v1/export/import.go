@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// Import parses obj, produced by Export, back into a Configuration and its
+// Revision history. It accepts both a *Resource ("resources" mode) and a
+// *metav1.List ("kubernetes" mode); the mode is inferred from the object's
+// type, not from any field inside it.
+//
+// Revision names are imported verbatim, so BYO revision names (see
+// Configuration.Spec.Template.ObjectMeta.Name and TestBYORevisionName)
+// continue to line up with the revisions that were previously created for
+// them, preserving the revision history on the target cluster.
+func Import(obj runtime.Object) (*servingv1.Configuration, []*servingv1.Revision, error) {
+	switch v := obj.(type) {
+	case *Resource:
+		return importResources(v)
+	case *metav1.List:
+		return importKubernetes(v)
+	default:
+		return nil, nil, fmt.Errorf("export: unsupported document type %T", obj)
+	}
+}
+
+func importResources(r *Resource) (*servingv1.Configuration, []*servingv1.Revision, error) {
+	cfg := r.Configuration.DeepCopy()
+	revs := make([]*servingv1.Revision, 0, len(r.Revisions))
+	for i := range r.Revisions {
+		revs = append(revs, r.Revisions[i].DeepCopy())
+	}
+	return cfg, revs, nil
+}
+
+func importKubernetes(l *metav1.List) (*servingv1.Configuration, []*servingv1.Revision, error) {
+	var cfg *servingv1.Configuration
+	var revs []*servingv1.Revision
+
+	for _, item := range l.Items {
+		kind, err := kindOf(item.Raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch kind {
+		case "Configuration":
+			if cfg != nil {
+				return nil, nil, fmt.Errorf("export: document contains more than one Configuration")
+			}
+			cfg = &servingv1.Configuration{}
+			if err := json.Unmarshal(item.Raw, cfg); err != nil {
+				return nil, nil, fmt.Errorf("export: decoding configuration: %w", err)
+			}
+		case "Revision":
+			rev := &servingv1.Revision{}
+			if err := json.Unmarshal(item.Raw, rev); err != nil {
+				return nil, nil, fmt.Errorf("export: decoding revision: %w", err)
+			}
+			revs = append(revs, rev)
+		default:
+			return nil, nil, fmt.Errorf("export: unexpected kind %q in document", kind)
+		}
+	}
+	if cfg == nil {
+		return nil, nil, fmt.Errorf("export: document does not contain a Configuration")
+	}
+	return cfg, revs, nil
+}
+
+func kindOf(raw []byte) (string, error) {
+	var tm metav1.TypeMeta
+	if err := json.Unmarshal(raw, &tm); err != nil {
+		return "", fmt.Errorf("export: decoding item type meta: %w", err)
+	}
+	return tm.Kind, nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// Mode selects the shape of the document produced by Export.
+type Mode string
+
+const (
+	// Resources embeds the Configuration and its Revisions as a single
+	// typed document. It is the more compact of the two forms and is meant
+	// for tooling that wants direct, typed access to both pieces.
+	Resources Mode = "resources"
+
+	// Kubernetes produces a kubectl-friendly `List` where every item is a
+	// standalone Revision, plus the Configuration itself, ready to be piped
+	// straight into `kubectl apply -f -`.
+	Kubernetes Mode = "kubernetes"
+)
+
+// Resource is the "resources" mode export document: a Configuration
+// alongside the full set of Revisions it has ever produced, ordered oldest
+// to newest (i.e. by increasing configurationGeneration).
+type Resource struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Configuration is the exported Configuration, with all defaulting
+	// already applied so that re-applying it is stable across versions of
+	// the target cluster's config-defaults ConfigMap.
+	Configuration servingv1.Configuration `json:"configuration"`
+
+	// Revisions is the Configuration's revision history, oldest first. Each
+	// entry retains its original name so that BYO revision names (see
+	// Configuration.Spec.Template.ObjectMeta.Name) round-trip unchanged.
+	Revisions []servingv1.Revision `json:"revisions"`
+}
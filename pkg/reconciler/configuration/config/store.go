@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config holds the context.Context-backed store of the
+// config-defaults and config-features ConfigMaps used while defaulting a
+// Configuration.
+package config
+
+import (
+	"context"
+
+	"knative.dev/pkg/configmap"
+	apicfg "knative.dev/serving/pkg/apis/config"
+)
+
+type cfgKey struct{}
+
+// Config is the schema of the configuration required by the Configuration
+// defaulting logic.
+type Config struct {
+	Defaults *apicfg.Defaults
+	Features *apicfg.Features
+}
+
+// FromContext fetches the Config stored by a Store in the context.
+func FromContext(ctx context.Context) *Config {
+	x, ok := ctx.Value(cfgKey{}).(*Config)
+	if ok {
+		return x
+	}
+	return nil
+}
+
+// FromContextOrDefaults is like FromContext, but when no Config is attached
+// it returns a Config populated with the fixed, hard-coded defaults rather
+// than nil, so that code need not special-case running outside of a
+// reconciler.
+func FromContextOrDefaults(ctx context.Context) *Config {
+	if cfg := FromContext(ctx); cfg != nil {
+		return cfg
+	}
+	defaults, _ := apicfg.NewDefaultsConfigFromMap(map[string]string{})
+	features, _ := apicfg.NewFeaturesConfigFromMap(map[string]string{})
+	return &Config{Defaults: defaults, Features: features}
+}
+
+// ToContext attaches the provided Config to the returned context, overriding
+// any previously attached Config.
+func ToContext(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, cfgKey{}, c)
+}
+
+// Store is a typed wrapper around configmap.Untyped store to handle our
+// configmaps.
+type Store struct {
+	*configmap.UntypedStore
+}
+
+// NewStore creates a new store of Configs and optionally calls functions
+// when ConfigMaps are updated.
+func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value interface{})) *Store {
+	store := &Store{
+		UntypedStore: configmap.NewUntypedStore(
+			"configuration",
+			logger,
+			configmap.Constructors{
+				apicfg.DefaultsConfigName: apicfg.NewDefaultsConfigFromConfigMap,
+				apicfg.FeaturesConfigName: apicfg.NewFeaturesConfigFromConfigMap,
+			},
+			onAfterStore...,
+		),
+	}
+	return store
+}
+
+// ToContext attaches the current Config from this Store onto the given
+// context.
+func (s *Store) ToContext(ctx context.Context) context.Context {
+	return ToContext(ctx, s.Load())
+}
+
+// Load creates a Config from the current config state of the Store.
+func (s *Store) Load() *Config {
+	return &Config{
+		Defaults: s.UntypedLoad(apicfg.DefaultsConfigName).(*apicfg.Defaults).DeepCopy(),
+		Features: s.UntypedLoad(apicfg.FeaturesConfigName).(*apicfg.Features).DeepCopy(),
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	logtesting "knative.dev/pkg/logging/testing"
+	apicfg "knative.dev/serving/pkg/apis/config"
+)
+
+func TestStoreLoadWithNamespaceOverrides(t *testing.T) {
+	store := NewStore(logtesting.TestLogger(t))
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: apicfg.FeaturesConfigName},
+	})
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: apicfg.DefaultsConfigName},
+		Data: map[string]string{
+			"revision-timeout-seconds":                   "300",
+			"revision-timeout-seconds.namespaces.team-a": "600",
+		},
+	})
+
+	ctx := store.ToContext(context.Background())
+	cfg := FromContext(ctx)
+
+	if got, want := *cfg.Defaults.Resolve("team-b", nil).RevisionTimeoutSeconds, int64(300); got != want {
+		t.Errorf("team-b RevisionTimeoutSeconds = %d, want %d", got, want)
+	}
+	if got, want := *cfg.Defaults.Resolve("team-a", nil).RevisionTimeoutSeconds, int64(600); got != want {
+		t.Errorf("team-a RevisionTimeoutSeconds = %d, want %d", got, want)
+	}
+}
+
+func TestStoreLoadWithSelectorOverrides(t *testing.T) {
+	store := NewStore(logtesting.TestLogger(t))
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: apicfg.FeaturesConfigName},
+	})
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: apicfg.DefaultsConfigName},
+		Data: map[string]string{
+			"revision-timeout-seconds": "300",
+			"selectors": `
+- matchLabels:
+    tier: batch
+  revision-timeout-seconds: "900"
+`,
+		},
+	})
+
+	ctx := store.ToContext(context.Background())
+	cfg := FromContext(ctx)
+
+	got := cfg.Defaults.Resolve("default", map[string]string{"tier": "batch"})
+	if *got.RevisionTimeoutSeconds != 900 {
+		t.Errorf("RevisionTimeoutSeconds = %d, want 900", *got.RevisionTimeoutSeconds)
+	}
+
+	got = cfg.Defaults.Resolve("default", map[string]string{"tier": "serving"})
+	if *got.RevisionTimeoutSeconds != 300 {
+		t.Errorf("RevisionTimeoutSeconds = %d, want 300", *got.RevisionTimeoutSeconds)
+	}
+}
+
+func TestFromContextOrDefaults(t *testing.T) {
+	cfg := FromContextOrDefaults(context.Background())
+	if cfg.Defaults == nil || cfg.Features == nil {
+		t.Fatal("FromContextOrDefaults() returned a Config with nil fields")
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configuration adds a dry-run defaulting endpoint for
+// Configuration, alongside the regular mutating defaulting webhook
+// registered by cmd/webhook.
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	cconfig "knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+// DryRunQueryParam is the query parameter that selects the dry-run
+// defaulting behavior, e.g. `POST /?dryRun=defaults`.
+const DryRunQueryParam = "dryRun"
+
+// DryRunDefaultsValue is the DryRunQueryParam value that requests a preview
+// of defaulting, as opposed to mutating admission.
+const DryRunDefaultsValue = "defaults"
+
+// dryRunResponse is the body returned by NewDryRunDefaultsHandler: the
+// Configuration as SetDefaults would leave it, and the list of changes that
+// produced it.
+type dryRunResponse struct {
+	Configuration *servingv1.Configuration  `json:"configuration"`
+	Changes       []servingv1.DefaultChange `json:"changes"`
+}
+
+// NewDryRunDefaultsHandler returns an http.Handler that, given a
+// Configuration in the request body, responds with the Configuration that
+// SetDefaults would produce and the list of changes it would make, without
+// admitting or persisting anything. It answers only requests carrying
+// `?dryRun=defaults`; this package does not register it on any server, so
+// it is reachable only where a caller mounts it. store supplies the live
+// config-defaults/config-features ConfigMap state, the same store the
+// mutating webhook uses, so the preview reflects the cluster's actual
+// configuration rather than only the built-in fallback defaults.
+func NewDryRunDefaultsHandler(store *cconfig.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get(DryRunQueryParam) != DryRunDefaultsValue {
+			http.Error(w, fmt.Sprintf("unsupported %s value", DryRunQueryParam), http.StatusBadRequest)
+			return
+		}
+
+		cfg := &servingv1.Configuration{}
+		if err := json.NewDecoder(r.Body).Decode(cfg); err != nil {
+			http.Error(w, fmt.Sprintf("decoding configuration: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		ctx := store.ToContext(r.Context())
+		defaulted, changes, err := cfg.SetDefaultsDryRun(ctx)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("computing defaults: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dryRunResponse{Configuration: defaulted, Changes: changes}); err != nil {
+			http.Error(w, fmt.Sprintf("encoding response: %v", err), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/serving/pkg/apis/config"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	cconfig "knative.dev/serving/pkg/reconciler/configuration/config"
+)
+
+const dryRunTimeoutSeconds = 123
+
+func TestNewDryRunDefaultsHandlerUsesStoreConfig(t *testing.T) {
+	store := cconfig.NewStore(logtesting.TestLogger(t))
+	store.OnConfigChanged(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: config.FeaturesConfigName}})
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.DefaultsConfigName},
+		Data: map[string]string{
+			"revision-timeout-seconds": "123",
+		},
+	})
+
+	body, err := json.Marshal(&servingv1.Configuration{
+		Spec: servingv1.ConfigurationSpec{
+			Template: servingv1.RevisionTemplateSpec{
+				Spec: servingv1.RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{{Image: "busybox"}},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/?dryRun=defaults", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	NewDryRunDefaultsHandler(store).ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var resp dryRunResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+
+	got := *resp.Configuration.Spec.Template.Spec.TimeoutSeconds
+	if got != dryRunTimeoutSeconds {
+		t.Errorf("timeoutSeconds = %d, want %d (from config-defaults ConfigMap)", got, dryRunTimeoutSeconds)
+	}
+}